@@ -0,0 +1,45 @@
+package kfnetwork
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+func (s *Server) HandleGlobalChatRequest(client net.Conn, packet GlobalChatRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	for _, p := range s.Clients {
+		s.SendGlobalChatResponse(p, player.Name, packet.Message)
+	}
+
+	logEntry := fmt.Sprintf("(Global Chat) %s: %s", player.Name, packet.Message)
+	Logger().Log(logEntry)
+	return nil
+}
+
+func (s *Server) HandleLobbyChatRequest(client net.Conn, packet LobbyChatRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	lobby := player.Lobby()
+
+	if lobby == nil {
+		return errors.New("player is not in a lobby")
+	}
+
+	response := LobbyChatResponsePacket{LobbyID: lobby.ID(), From: player.Name, Message: packet.Message}
+	response.Type = PacketTypeLobbyChatResponse
+	lobby.Broadcast(response)
+
+	logEntry := fmt.Sprintf("(Lobby %s Chat) %s: %s", lobby.ID(), player.Name, packet.Message)
+	Logger().Log(logEntry)
+	return nil
+}
@@ -2,18 +2,120 @@ package kfnetwork
 
 import (
 	"net"
+	"sync"
 )
 
 type Client struct {
 	Connection net.Conn
 	Sequence   uint16
+
+	// GameConnection is the dedicated in-game socket opened by
+	// ConnectToGame, separate from Connection (which stays on the
+	// lobby/login socket).
+	GameConnection net.Conn
+
+	seqMu sync.Mutex
+
+	handlersMu sync.Mutex
+	handlers   map[PacketType]PacketHandler
+
+	// ChatEvents, LobbyEvents, GameStartEvents, MatchEvents, and Errors
+	// carry unsolicited packets pushed by the server — chat, presence,
+	// matchmaking, and game-start notifications preempt the normal
+	// request/response flow, so Run() dispatches them here instead of
+	// making callers poll.
+	ChatEvents      chan ChatEvent
+	LobbyEvents     chan LobbyPlayerStatusPacket
+	GameStartEvents chan StartGamePacket
+	MatchEvents     chan MatchFoundPacket
+	Errors          chan ErrorPacket
 }
 
+// ChatEvent is the unified shape pushed onto ChatEvents for both global and
+// lobby chat; LobbyID is empty for global chat.
+type ChatEvent struct {
+	LobbyID string
+	From    string
+	Message string
+}
+
+// PacketHandler is a callback registered via Client.OnPacket.
+type PacketHandler func(Packet)
+
 func NewClient() *Client {
 	client := new(Client)
+	client.handlers = make(map[PacketType]PacketHandler)
+	client.ChatEvents = make(chan ChatEvent, 16)
+	client.LobbyEvents = make(chan LobbyPlayerStatusPacket, 16)
+	client.GameStartEvents = make(chan StartGamePacket, 16)
+	client.MatchEvents = make(chan MatchFoundPacket, 16)
+	client.Errors = make(chan ErrorPacket, 16)
 	return client
 }
 
+// bumpSequence increments Sequence under a mutex so concurrent senders
+// don't race on it.
+func (c *Client) bumpSequence() {
+	c.seqMu.Lock()
+	c.Sequence++
+	c.seqMu.Unlock()
+}
+
+// OnPacket registers handler to receive every packet of type t read by
+// Run(), instead of the built-in event channels. Registering a handler for a type Run() already dispatches to a
+// typed channel takes over that type entirely, so third-party code can
+// extend the protocol without forking the dispatch logic.
+func (c *Client) OnPacket(t PacketType, handler PacketHandler) {
+	c.handlersMu.Lock()
+	c.handlers[t] = handler
+	c.handlersMu.Unlock()
+}
+
+// Run starts a background read loop that dispatches packets pushed by the
+// server onto Client's typed event channels (or a handler registered via
+// OnPacket) as they arrive, rather than only in response to a request this
+// client sent.
+func (c *Client) Run() {
+	go func() {
+		for {
+			packet, e := ReadPacket(c.Connection)
+
+			if e != nil {
+				c.Errors <- ErrorPacket{Message: e.Error()}
+				return
+			}
+
+			c.dispatch(packet)
+		}
+	}()
+}
+
+func (c *Client) dispatch(packet Packet) {
+	c.handlersMu.Lock()
+	handler, ok := c.handlers[packet.GetHeader().Type]
+	c.handlersMu.Unlock()
+
+	if ok {
+		handler(packet)
+		return
+	}
+
+	switch p := packet.(type) {
+	case GlobalChatResponsePacket:
+		c.ChatEvents <- ChatEvent{From: p.From, Message: p.Message}
+	case LobbyChatResponsePacket:
+		c.ChatEvents <- ChatEvent{LobbyID: p.LobbyID, From: p.From, Message: p.Message}
+	case LobbyPlayerStatusPacket:
+		c.LobbyEvents <- p
+	case StartGamePacket:
+		c.GameStartEvents <- p
+	case MatchFoundPacket:
+		c.MatchEvents <- p
+	case ErrorPacket:
+		c.Errors <- p
+	}
+}
+
 func (c *Client) Connect(address string) error {
 	var e error
 	c.Connection, e = net.Dial("tcp", address)
@@ -31,7 +133,7 @@ func (c *Client) SendVersionRequest() error {
 	packet.Version = ProtocolVersion
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -40,7 +142,7 @@ func (c *Client) SendExitRequest() error {
 	packet.Type = PacketTypeExit
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -52,7 +154,7 @@ func (c *Client) SendLoginRequest(name string, id string, token string) error {
 	packet.Token = token
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -62,7 +164,7 @@ func (c *Client) SendCreateLobbyRequest(name string) error {
 	packet.Name = name
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -72,7 +174,7 @@ func (c *Client) SendGetCardPile(pile uint8) error {
 	packet.Pile = pile
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -81,7 +183,7 @@ func (c *Client) SendPlayerListRequest() error {
 	packet.Type = PacketTypePlayerListRequest
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -91,7 +193,7 @@ func (c *Client) SendGlobalChatRequest(message string) error {
 	packet.Message = message
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -100,7 +202,7 @@ func (c *Client) SendLobbyListRequest() error {
 	packet.Type = PacketTypeLobbyListRequest
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
@@ -110,10 +212,77 @@ func (c *Client) SendJoinLobbyRequest(query string) error {
 	packet.Name = query
 
 	e := WritePacket(c.Connection, packet)
-	c.Sequence++
+	c.bumpSequence()
 	return e
 }
 
 func (c *Client) SendGetArchivePile() {
 	c.SendGetCardPile(CardPileArchive)
 }
+
+func (c *Client) SendLobbyChatRequest(message string) error {
+	packet := LobbyChatRequestPacket{}
+	packet.Type = PacketTypeLobbyChatRequest
+	packet.Message = message
+
+	e := WritePacket(c.Connection, packet)
+	c.bumpSequence()
+	return e
+}
+
+func (c *Client) SendLeaveLobbyRequest() error {
+	packet := LeaveLobbyRequestPacket{}
+	packet.Type = PacketTypeLeaveLobbyRequest
+
+	e := WritePacket(c.Connection, packet)
+	c.bumpSequence()
+	return e
+}
+
+// ConnectToGame dials the game address from a StartGamePacket, opens
+// GameConnection, and sends gameID/token as the first frame so the server
+// can pair this connection into the right GameSession.
+func (c *Client) ConnectToGame(address string, gameID string, token string) error {
+	conn, e := net.Dial("tcp", address)
+
+	if e != nil {
+		return e
+	}
+
+	c.GameConnection = conn
+
+	packet := GameJoinPacket{GameID: gameID, Token: token}
+	packet.Type = PacketTypeGameJoin
+
+	return WritePacket(c.GameConnection, packet)
+}
+
+// SendGameMessage relays payload to the other player(s) in the game
+// session via the server's router.
+func (c *Client) SendGameMessage(payload string) error {
+	packet := GameMessagePacket{Payload: payload}
+	packet.Type = PacketTypeGameMessage
+
+	return WritePacket(c.GameConnection, packet)
+}
+
+// SendQueueForGameRequest enters this client into the server's matchmaking
+// queue.
+func (c *Client) SendQueueForGameRequest() error {
+	packet := QueueForGameRequestPacket{}
+	packet.Type = PacketTypeQueueForGameRequest
+
+	e := WritePacket(c.Connection, packet)
+	c.bumpSequence()
+	return e
+}
+
+// SendQueueCancelRequest drops this client out of the matchmaking queue.
+func (c *Client) SendQueueCancelRequest() error {
+	packet := QueueCancelRequestPacket{}
+	packet.Type = PacketTypeQueueCancelRequest
+
+	e := WritePacket(c.Connection, packet)
+	c.bumpSequence()
+	return e
+}
@@ -0,0 +1,82 @@
+package kfnetwork
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClientDispatchRoutesToTypedChannel(t *testing.T) {
+	c := NewClient()
+
+	status := LobbyPlayerStatusPacket{LobbyID: "lobby-1", PlayerName: "ash", Joined: true}
+	status.Type = PacketTypeLobbyPlayerStatus
+
+	c.dispatch(status)
+
+	select {
+	case got := <-c.LobbyEvents:
+		if got != status {
+			t.Fatalf("got %+v, want %+v", got, status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for LobbyEvents")
+	}
+}
+
+func TestClientOnPacketOverridesTypedChannel(t *testing.T) {
+	c := NewClient()
+
+	var got Packet
+	c.OnPacket(PacketTypeLobbyPlayerStatus, func(p Packet) {
+		got = p
+	})
+
+	status := LobbyPlayerStatusPacket{LobbyID: "lobby-1", PlayerName: "ash", Joined: true}
+	status.Type = PacketTypeLobbyPlayerStatus
+
+	c.dispatch(status)
+
+	if got != status {
+		t.Fatalf("got %+v, want the registered handler to receive %+v", got, status)
+	}
+
+	select {
+	case e := <-c.LobbyEvents:
+		t.Fatalf("expected registering an OnPacket handler to take over dispatch, but LobbyEvents also got %+v", e)
+	default:
+	}
+}
+
+func TestClientRunDispatchesUntilConnectionCloses(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+
+	c := NewClient()
+	c.Connection = client
+	c.Run()
+
+	status := LobbyPlayerStatusPacket{LobbyID: "lobby-1", PlayerName: "ash", Joined: true}
+	status.Type = PacketTypeLobbyPlayerStatus
+
+	if e := WritePacket(server, status); e != nil {
+		t.Fatalf("unexpected error writing packet: %s", e)
+	}
+
+	select {
+	case got := <-c.LobbyEvents:
+		if got != status {
+			t.Fatalf("got %+v, want %+v", got, status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Run() to dispatch the packet")
+	}
+
+	server.Close()
+
+	select {
+	case <-c.Errors:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Run() to push an error once the connection closed")
+	}
+}
@@ -0,0 +1,158 @@
+package kfnetwork
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// HandlerFunc handles a single decoded packet from client. It's the shape
+// every entry in Server's dispatch table is stored as; the built-in
+// Handle*Request methods keep their concrete packet types and are adapted
+// into this shape when registered.
+type HandlerFunc func(net.Conn, Packet) error
+
+// Middleware wraps a HandlerFunc with cross-cutting behavior (auth,
+// rate-limiting, lobby membership, ...) that should run before it.
+type Middleware func(HandlerFunc) HandlerFunc
+
+// chain applies middlewares to handler in order, so the first middleware
+// passed runs first.
+func chain(handler HandlerFunc, middlewares ...Middleware) HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// RegisterHandler wires handler up to run (behind middlewares, in order)
+// whenever a packet of type t arrives. Third-party code can call this to
+// extend the protocol without forking HandlePacket.
+func (s *Server) RegisterHandler(t PacketType, handler HandlerFunc, middlewares ...Middleware) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[PacketType]HandlerFunc)
+	}
+
+	s.handlers[t] = chain(handler, middlewares...)
+}
+
+// HandlePacket rate-limits every incoming packet regardless of whether a
+// handler is registered for it — an unhandled type still costs the
+// connection a token, so flooding one can't dodge the limiter — then looks
+// up the handler registered for packet's type and runs it. Unregistered
+// packet types are silently ignored past that point, matching the
+// pre-registry switch's behavior.
+func (s *Server) HandlePacket(client net.Conn, packet Packet) {
+	if !s.allowPacket(client, packet.GetHeader().Type) {
+		return
+	}
+
+	s.handlersMu.Lock()
+	handler, ok := s.handlers[packet.GetHeader().Type]
+	s.handlersMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if e := handler(client, packet); e != nil && s.Debug {
+		logEntry := fmt.Sprintf("handler for packet type %d: %s", packet.GetHeader().Type, e.Error())
+		Logger().Log(logEntry)
+	}
+}
+
+// registerDefaultHandlers wires up the built-in protocol handlers. It runs
+// once, from NewServer.
+func (s *Server) registerDefaultHandlers() {
+	authed := authRequiredMiddleware(s)
+	inLobby := lobbyRequiredMiddleware(s)
+
+	s.RegisterHandler(PacketTypeVersionRequest, func(client net.Conn, packet Packet) error {
+		s.HandleVersionRequest(client, packet.(VersionPacket))
+		return nil
+	})
+
+	s.RegisterHandler(PacketTypeExit, func(client net.Conn, packet Packet) error {
+		return s.HandleExitRequest(client, packet.(ExitPacket))
+	})
+
+	s.RegisterHandler(PacketTypeLoginRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleLoginRequest(client, packet.(LoginRequestPacket))
+	})
+
+	s.RegisterHandler(PacketTypePlayerListRequest, func(client net.Conn, packet Packet) error {
+		return s.HandlePlayerListRequest(client, packet.(PlayerListRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeGlobalChatRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleGlobalChatRequest(client, packet.(GlobalChatRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeLobbyChatRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleLobbyChatRequest(client, packet.(LobbyChatRequestPacket))
+	}, authed, inLobby)
+
+	s.RegisterHandler(PacketTypeCreateLobbyRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleCreateLobbyRequest(client, packet.(CreateLobbyRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeLobbyListRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleLobbyListRequest(client, packet.(LobbyListRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeJoinLobbyRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleJoinLobbyRequest(client, packet.(JoinLobbyRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeLeaveLobbyRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleLeaveLobbyRequest(client, packet.(LeaveLobbyRequestPacket))
+	}, authed, inLobby)
+
+	s.RegisterHandler(PacketTypeQueueForGameRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleQueueForGameRequest(client, packet.(QueueForGameRequestPacket))
+	}, authed)
+
+	s.RegisterHandler(PacketTypeQueueCancelRequest, func(client net.Conn, packet Packet) error {
+		return s.HandleQueueCancelRequest(client, packet.(QueueCancelRequestPacket))
+	}, authed)
+}
+
+// authRequiredMiddleware rejects the request unless client is a logged-in
+// player.
+func authRequiredMiddleware(s *Server) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(client net.Conn, packet Packet) error {
+			if _, e := s.FindPlayerByConnection(client); e != nil {
+				s.SendErrorPacket(client, "authentication required")
+				return e
+			}
+
+			return next(client, packet)
+		}
+	}
+}
+
+// lobbyRequiredMiddleware rejects the request unless client's player is
+// currently seated in a lobby. It must run after authRequiredMiddleware.
+func lobbyRequiredMiddleware(s *Server) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(client net.Conn, packet Packet) error {
+			player, e := s.FindPlayerByConnection(client)
+
+			if e != nil {
+				return e
+			}
+
+			if player.Lobby() == nil {
+				s.SendErrorPacket(client, "not in a lobby")
+				return errors.New("player is not in a lobby")
+			}
+
+			return next(client, packet)
+		}
+	}
+}
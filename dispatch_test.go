@@ -0,0 +1,99 @@
+package kfnetwork
+
+import (
+	"net"
+	"testing"
+)
+
+func TestChainRunsMiddlewaresInOrder(t *testing.T) {
+	var order []string
+
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(client net.Conn, packet Packet) error {
+				order = append(order, name)
+				return next(client, packet)
+			}
+		}
+	}
+
+	handler := chain(func(client net.Conn, packet Packet) error {
+		order = append(order, "handler")
+		return nil
+	}, record("first"), record("second"))
+
+	if e := handler(nil, nil); e != nil {
+		t.Fatalf("unexpected error: %s", e)
+	}
+
+	want := []string{"first", "second", "handler"}
+
+	if len(order) != len(want) {
+		t.Fatalf("got order %v, want %v", order, want)
+	}
+
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRegisterHandlerDispatchesByPacketType(t *testing.T) {
+	s := NewServer()
+	called := false
+
+	s.RegisterHandler(PacketTypeGlobalChatRequest, func(client net.Conn, packet Packet) error {
+		called = true
+		return nil
+	})
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	packet := GlobalChatRequestPacket{Message: "hi"}
+	packet.Type = PacketTypeGlobalChatRequest
+
+	s.HandlePacket(server, packet)
+
+	if !called {
+		t.Fatal("expected the registered handler for PacketTypeGlobalChatRequest to run")
+	}
+}
+
+func TestHandlePacketIgnoresUnregisteredType(t *testing.T) {
+	s := NewServer()
+	s.handlersMu.Lock()
+	s.handlers = make(map[PacketType]HandlerFunc)
+	s.handlersMu.Unlock()
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	packet := GlobalChatRequestPacket{Message: "hi"}
+	packet.Type = PacketTypeGlobalChatRequest
+
+	// Should not panic despite no handler being registered for this type.
+	s.HandlePacket(server, packet)
+}
+
+func TestDequeuePlayerRemovesOnlyMatchingPlayer(t *testing.T) {
+	s := NewServer()
+	a, b, c := NewPlayer(), NewPlayer(), NewPlayer()
+	s.queue = []*Player{a, b, c}
+
+	s.dequeuePlayer(b)
+
+	if len(s.queue) != 2 || s.queue[0] != a || s.queue[1] != c {
+		t.Fatalf("got queue %v, want [a, c]", s.queue)
+	}
+
+	// Removing a player not in the queue is a no-op, not an error.
+	s.dequeuePlayer(b)
+
+	if len(s.queue) != 2 {
+		t.Fatalf("expected dequeuing an absent player to leave the queue untouched, got %v", s.queue)
+	}
+}
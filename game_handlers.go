@@ -0,0 +1,150 @@
+package kfnetwork
+
+import (
+	"fmt"
+	"net"
+)
+
+// DefaultMatchSize is the number of players the Usher groups into a lobby
+// when none is configured on the Server.
+const DefaultMatchSize = 2
+
+// StartUsher starts the goroutine that pairs players off the matchmaking
+// queue into lobbies. Call it once during server startup.
+func (s *Server) StartUsher() {
+	if s.MatchSize == 0 {
+		s.MatchSize = DefaultMatchSize
+	}
+
+	go s.usherLoop()
+}
+
+// usherLoop wakes up whenever the queue changes and forms as many matches
+// as it can out of whoever is waiting.
+func (s *Server) usherLoop() {
+	for range s.usherWake {
+		s.formMatches()
+	}
+}
+
+func (s *Server) formMatches() {
+	for {
+		group := s.dequeueMatch()
+
+		if group == nil {
+			return
+		}
+
+		s.startMatch(group)
+	}
+}
+
+// dequeueMatch pops MatchSize players off the front of the queue, or
+// returns nil if there aren't enough waiting yet.
+func (s *Server) dequeueMatch() []*Player {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if len(s.queue) < s.MatchSize {
+		return nil
+	}
+
+	group := make([]*Player, s.MatchSize)
+	copy(group, s.queue[:s.MatchSize])
+	s.queue = s.queue[s.MatchSize:]
+	return group
+}
+
+func (s *Server) startMatch(group []*Player) {
+	lobby := s.AddLobby(group[0], "Ranked Match")
+
+	for _, player := range group[1:] {
+		lobby.AddPlayer(player)
+		s.broadcastLobbyStatus(lobby, player, true)
+	}
+
+	for _, player := range group {
+		packet := MatchFoundPacket{LobbyID: lobby.ID()}
+		packet.Type = PacketTypeMatchFound
+
+		if e := WritePacket(player.Client, packet); e != nil {
+			logEntry := fmt.Sprintf("Usher: failed to notify %s: %s", player.Name, e.Error())
+			Logger().Error(logEntry)
+		}
+	}
+
+	logEntry := fmt.Sprintf("Usher matched %d players into lobby %s", len(group), lobby.ID())
+	Logger().Log(logEntry)
+
+	// A matchmade lobby is ready to play the moment it's formed, so start
+	// its game session immediately rather than waiting on an explicit
+	// "start game" request from the lobby owner.
+	if _, e := s.StartGame(lobby); e != nil {
+		logEntry := fmt.Sprintf("Usher: failed to start game for lobby %s: %s", lobby.ID(), e.Error())
+		Logger().Error(logEntry)
+	}
+}
+
+// HandleQueueForGameRequest enqueues the requesting player for matchmaking
+// and wakes the Usher to see if a match can now be formed. Players already
+// in a lobby or already queued are left alone, so a retried request can't
+// seat the same player into a match twice.
+func (s *Server) HandleQueueForGameRequest(client net.Conn, packet QueueForGameRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	if player.Lobby() != nil {
+		return nil
+	}
+
+	s.queueMu.Lock()
+	for _, p := range s.queue {
+		if p == player {
+			s.queueMu.Unlock()
+			return nil
+		}
+	}
+	s.queue = append(s.queue, player)
+	s.queueMu.Unlock()
+
+	s.wakeUsher()
+	return nil
+}
+
+// HandleQueueCancelRequest drops the requesting player out of the
+// matchmaking queue, if they're in it.
+func (s *Server) HandleQueueCancelRequest(client net.Conn, packet QueueCancelRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	s.dequeuePlayer(player)
+	return nil
+}
+
+// dequeuePlayer removes player from the matchmaking queue, if present. It's
+// safe to call for a player who was never queued.
+func (s *Server) dequeuePlayer(player *Player) {
+	s.queueMu.Lock()
+	for i, p := range s.queue {
+		if p == player {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+	s.queueMu.Unlock()
+}
+
+// wakeUsher signals the Usher loop without blocking if it's already busy
+// forming matches.
+func (s *Server) wakeUsher() {
+	select {
+	case s.usherWake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,102 @@
+package kfnetwork
+
+import (
+	"net"
+	"sync"
+)
+
+// GameSession pairs the connections of the players in a single match and
+// relays GameMessage packets between them. It's created by Server.StartGame
+// and torn down as soon as any one of its connections disconnects, since a
+// match can't continue with a player missing.
+type GameSession struct {
+	id    string
+	token string
+
+	mu      sync.Mutex
+	clients []net.Conn
+	closed  bool
+
+	onEmpty func()
+}
+
+func newGameSession(id string, token string, onEmpty func()) *GameSession {
+	return &GameSession{id: id, token: token, onEmpty: onEmpty}
+}
+
+func (g *GameSession) ID() string {
+	return g.id
+}
+
+// Join admits conn into the session and starts relaying GameMessage packets
+// from it to every other connection already in the session.
+func (g *GameSession) Join(conn net.Conn) {
+	g.mu.Lock()
+	g.clients = append(g.clients, conn)
+	g.mu.Unlock()
+
+	go g.route(conn)
+}
+
+// route reads packets off conn until it disconnects, forwarding each
+// GameMessage to the session's other connections. Any one side dropping
+// ends the match, so it tears down the whole session.
+func (g *GameSession) route(conn net.Conn) {
+	for {
+		packet, e := ReadPacket(conn)
+
+		if e != nil {
+			g.teardown()
+			return
+		}
+
+		if packet.GetHeader().Type == PacketTypeGameMessage {
+			g.broadcast(conn, packet)
+		}
+	}
+}
+
+func (g *GameSession) broadcast(sender net.Conn, packet Packet) {
+	for _, conn := range g.Clients() {
+		if conn == sender {
+			continue
+		}
+
+		WritePacket(conn, packet)
+	}
+}
+
+// Clients returns a snapshot of the connections currently in the session.
+func (g *GameSession) Clients() []net.Conn {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clients := make([]net.Conn, len(g.clients))
+	copy(clients, g.clients)
+	return clients
+}
+
+// teardown closes every remaining connection in the session and fires
+// onEmpty exactly once, however many of the session's connections call it
+// concurrently.
+func (g *GameSession) teardown() {
+	g.mu.Lock()
+
+	if g.closed {
+		g.mu.Unlock()
+		return
+	}
+
+	g.closed = true
+	clients := g.clients
+	g.clients = nil
+	g.mu.Unlock()
+
+	for _, conn := range clients {
+		conn.Close()
+	}
+
+	if g.onEmpty != nil {
+		g.onEmpty()
+	}
+}
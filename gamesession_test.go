@@ -0,0 +1,83 @@
+package kfnetwork
+
+import (
+	"net"
+	"sync"
+	"testing"
+)
+
+func TestGameSessionTeardownClosesAllClients(t *testing.T) {
+	a1, a2 := net.Pipe()
+	b1, b2 := net.Pipe()
+	defer a2.Close()
+	defer b2.Close()
+
+	g := newGameSession("game-1", "token", nil)
+	g.Join(a1)
+	g.Join(b1)
+
+	g.teardown()
+
+	if _, e := a1.Write([]byte("x")); e == nil {
+		t.Fatal("expected writing to a torn-down session's connection to fail")
+	}
+
+	if _, e := b1.Write([]byte("x")); e == nil {
+		t.Fatal("expected writing to a torn-down session's connection to fail")
+	}
+}
+
+func TestGameSessionTeardownFiresOnEmptyExactlyOnce(t *testing.T) {
+	calls := 0
+	g := newGameSession("game-1", "token", func() { calls++ })
+
+	g.teardown()
+	g.teardown()
+	g.teardown()
+
+	if calls != 1 {
+		t.Fatalf("got %d onEmpty calls, want 1", calls)
+	}
+}
+
+func TestGameSessionTeardownIsSafeUnderConcurrentDisconnect(t *testing.T) {
+	var onEmptyCalls int32
+	var mu sync.Mutex
+
+	g := newGameSession("game-1", "token", func() {
+		mu.Lock()
+		onEmptyCalls++
+		mu.Unlock()
+	})
+
+	a1, a2 := net.Pipe()
+	b1, b2 := net.Pipe()
+	defer a1.Close()
+	defer a2.Close()
+	defer b1.Close()
+	defer b2.Close()
+
+	g.Join(a1)
+	g.Join(b1)
+
+	// Simulate both sides of the match disconnecting at the same instant,
+	// the way route() would call teardown concurrently from two goroutines.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer wg.Done()
+			g.teardown()
+		}()
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if onEmptyCalls != 1 {
+		t.Fatalf("got %d onEmpty calls from concurrent teardowns, want exactly 1", onEmptyCalls)
+	}
+}
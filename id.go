@@ -0,0 +1,14 @@
+package kfnetwork
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID returns a random hex identifier used for lobby IDs, game session
+// IDs, and anything else that just needs to be unique and URL-safe.
+func newID() string {
+	buffer := make([]byte, 8)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
@@ -0,0 +1,69 @@
+package kfnetwork
+
+import "sync"
+
+// Lobby groups a set of players waiting to start a game together. It owns
+// its own roster and is responsible for fanning out lobby-scoped chat and
+// presence events to exactly the players sitting in it.
+type Lobby struct {
+	id   string
+	name string
+
+	mu      sync.Mutex
+	players []*Player
+}
+
+// NewLobby returns an empty lobby with the given display name.
+func NewLobby(name string) *Lobby {
+	return &Lobby{id: newID(), name: name}
+}
+
+func (l *Lobby) ID() string {
+	return l.id
+}
+
+func (l *Lobby) Name() string {
+	return l.name
+}
+
+// Players returns a snapshot of the lobby's current occupants.
+func (l *Lobby) Players() []*Player {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	players := make([]*Player, len(l.players))
+	copy(players, l.players)
+	return players
+}
+
+// AddPlayer seats player in the lobby and points the player back at it.
+func (l *Lobby) AddPlayer(player *Player) {
+	l.mu.Lock()
+	l.players = append(l.players, player)
+	l.mu.Unlock()
+
+	player.SetLobby(l)
+}
+
+// RemovePlayer removes player from the lobby, if present.
+func (l *Lobby) RemovePlayer(player *Player) {
+	l.mu.Lock()
+	for i, p := range l.players {
+		if p == player {
+			l.players = append(l.players[:i], l.players[i+1:]...)
+			break
+		}
+	}
+	l.mu.Unlock()
+
+	if player.Lobby() == l {
+		player.SetLobby(nil)
+	}
+}
+
+// Broadcast sends packet to every player currently in the lobby.
+func (l *Lobby) Broadcast(packet Packet) {
+	for _, p := range l.Players() {
+		WritePacket(p.Client, packet)
+	}
+}
@@ -0,0 +1,119 @@
+package kfnetwork
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+func (s *Server) HandleCreateLobbyRequest(client net.Conn, packet CreateLobbyRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		if s.Debug {
+			logEntry := fmt.Sprintf("HandleCreateLobbyRequest: %s", e.Error())
+			Logger().Log(logEntry)
+		}
+
+		return e
+	}
+
+	lobby := s.AddLobby(player, packet.Name)
+
+	logEntry := fmt.Sprintf("Player %s created lobby %s (%s)", player.Name, lobby.name, lobby.ID())
+	Logger().Log(logEntry)
+
+	e = s.SendCreateLobbyResponse(player, lobby.ID())
+	return e
+}
+
+func (s *Server) HandleLobbyListRequest(client net.Conn, packet LobbyListRequestPacket) error {
+	lobbyList := LobbyList{}
+
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	for _, lobby := range s.Lobbies {
+		entry := LobbyListEntry{ID: lobby.ID(), Name: lobby.Name()}
+		lobbyList.Lobbies = append(lobbyList.Lobbies, entry)
+	}
+
+	lobbyList.Count = uint(len(lobbyList.Lobbies))
+
+	s.SendLobbyListResponse(player, lobbyList)
+
+	logEntry := fmt.Sprintf("Player %s requested a lobby list.", player.Name)
+	Logger().Log(logEntry)
+	return nil
+}
+
+func (s *Server) HandleJoinLobbyRequest(client net.Conn, packet JoinLobbyRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	lobby, e := s.FindLobbyByID(packet.ID)
+
+	if e == nil {
+		lobby.AddPlayer(player)
+		s.broadcastLobbyStatus(lobby, player, true)
+		s.SendJoinLobbyResponse(player, lobby.name, lobby.ID(), true)
+		return nil
+	}
+
+	lobby, e = s.FindLobbyByName(packet.Name)
+
+	if e == nil {
+		lobby.AddPlayer(player)
+		s.broadcastLobbyStatus(lobby, player, true)
+		s.SendJoinLobbyResponse(player, lobby.name, lobby.ID(), true)
+		return nil
+	}
+
+	return errors.New("no lobby found")
+}
+
+// HandleLeaveLobbyRequest removes the requesting player from their current
+// lobby, if any, and notifies the players left behind.
+func (s *Server) HandleLeaveLobbyRequest(client net.Conn, packet LeaveLobbyRequestPacket) error {
+	player, e := s.FindPlayerByConnection(client)
+
+	if e != nil {
+		return e
+	}
+
+	if player.Lobby() == nil {
+		return errors.New("player is not in a lobby")
+	}
+
+	s.leaveLobby(player)
+	return nil
+}
+
+// leaveLobby removes player from their current lobby and broadcasts the
+// departure to whoever is left.
+func (s *Server) leaveLobby(player *Player) {
+	lobby := player.Lobby()
+	lobby.RemovePlayer(player)
+	s.broadcastLobbyStatus(lobby, player, false)
+}
+
+// broadcastLobbyStatus pushes a LobbyPlayerStatusPacket for player to every
+// other occupant of lobby.
+func (s *Server) broadcastLobbyStatus(lobby *Lobby, player *Player, joined bool) {
+	status := LobbyPlayerStatusPacket{LobbyID: lobby.ID(), PlayerName: player.Name, Joined: joined}
+	status.Type = PacketTypeLobbyPlayerStatus
+
+	for _, p := range lobby.Players() {
+		if p == player {
+			continue
+		}
+
+		WritePacket(p.Client, status)
+	}
+}
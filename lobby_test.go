@@ -0,0 +1,111 @@
+package kfnetwork
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLobbyAddPlayerSeatsAndPointsBack(t *testing.T) {
+	lobby := NewLobby("Test Lobby")
+	player := NewPlayer()
+
+	lobby.AddPlayer(player)
+
+	players := lobby.Players()
+
+	if len(players) != 1 || players[0] != player {
+		t.Fatalf("got players %v, want [player]", players)
+	}
+
+	if player.Lobby() != lobby {
+		t.Fatal("expected AddPlayer to point the player back at the lobby")
+	}
+}
+
+func TestLobbyRemovePlayerClearsBackReference(t *testing.T) {
+	lobby := NewLobby("Test Lobby")
+	player := NewPlayer()
+	lobby.AddPlayer(player)
+
+	lobby.RemovePlayer(player)
+
+	if len(lobby.Players()) != 0 {
+		t.Fatalf("got players %v, want none", lobby.Players())
+	}
+
+	if player.Lobby() != nil {
+		t.Fatal("expected RemovePlayer to clear the player's lobby back-reference")
+	}
+}
+
+func TestLobbyBroadcastReachesEveryOccupant(t *testing.T) {
+	lobby := NewLobby("Test Lobby")
+
+	aServer, aClient := net.Pipe()
+	bServer, bClient := net.Pipe()
+	defer aServer.Close()
+	defer aClient.Close()
+	defer bServer.Close()
+	defer bClient.Close()
+
+	a, b := NewPlayer(), NewPlayer()
+	a.Client, b.Client = aServer, bServer
+	lobby.AddPlayer(a)
+	lobby.AddPlayer(b)
+
+	packet := LobbyChatResponsePacket{LobbyID: lobby.ID(), From: "ash", Message: "hi"}
+	packet.Type = PacketTypeLobbyChatResponse
+
+	done := make(chan error, 2)
+	go func() {
+		_, e := ReadPacket(aClient)
+		done <- e
+	}()
+	go func() {
+		_, e := ReadPacket(bClient)
+		done <- e
+	}()
+
+	lobby.Broadcast(packet)
+
+	for i := 0; i < 2; i++ {
+		if e := <-done; e != nil {
+			t.Fatalf("unexpected error reading broadcast packet: %s", e)
+		}
+	}
+}
+
+func TestBroadcastLobbyStatusExcludesTheSubjectPlayer(t *testing.T) {
+	s := NewServer()
+	lobby := NewLobby("Test Lobby")
+
+	joinerServer, _ := net.Pipe()
+	defer joinerServer.Close()
+
+	otherServer, otherClient := net.Pipe()
+	defer otherServer.Close()
+	defer otherClient.Close()
+
+	joiner, other := NewPlayer(), NewPlayer()
+	joiner.Client, other.Client = joinerServer, otherServer
+	lobby.AddPlayer(other)
+	lobby.AddPlayer(joiner)
+
+	done := make(chan Packet, 1)
+	go func() {
+		packet, _ := ReadPacket(otherClient)
+		done <- packet
+	}()
+
+	s.broadcastLobbyStatus(lobby, joiner, true)
+
+	status, ok := (<-done).(LobbyPlayerStatusPacket)
+
+	if !ok {
+		t.Fatal("expected the other occupant to receive a LobbyPlayerStatusPacket")
+	}
+
+	if status.PlayerName != joiner.Name || !status.Joined {
+		t.Fatalf("got %+v, want Joined status for %q", status, joiner.Name)
+	}
+}
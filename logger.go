@@ -0,0 +1,35 @@
+package kfnetwork
+
+import (
+	"log"
+	"os"
+	"sync"
+)
+
+// logger is a minimal leveled wrapper around the standard library logger.
+// It's deliberately small: the server only needs Log/Error today.
+type logger struct {
+	std *log.Logger
+}
+
+func (l *logger) Log(message string) {
+	l.std.Println(message)
+}
+
+func (l *logger) Error(message string) {
+	l.std.Println("ERROR:", message)
+}
+
+var (
+	defaultLogger     *logger
+	defaultLoggerOnce sync.Once
+)
+
+// Logger returns the process-wide logger instance.
+func Logger() *logger {
+	defaultLoggerOnce.Do(func() {
+		defaultLogger = &logger{std: log.New(os.Stdout, "", log.LstdFlags)}
+	})
+
+	return defaultLogger
+}
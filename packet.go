@@ -0,0 +1,335 @@
+package kfnetwork
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+)
+
+// ProtocolVersion is the wire protocol version this build of the client and
+// server speak. HandleVersionRequest rejects any peer that doesn't match it.
+const ProtocolVersion = "1.0"
+
+// PacketType identifies the concrete packet carried in a frame's header so
+// the receiving side knows which struct to decode the body into.
+type PacketType uint8
+
+const (
+	PacketTypeVersionRequest PacketType = iota
+	PacketTypeExit
+	PacketTypeLoginRequest
+	PacketTypeCreateLobbyRequest
+	PacketTypeCreateLobbyResponse
+	PacketTypeCardPileRequest
+	PacketTypePlayerListRequest
+	PacketTypePlayerListResponse
+	PacketTypeGlobalChatRequest
+	PacketTypeGlobalChatResponse
+	PacketTypeLobbyListRequest
+	PacketTypeLobbyListResponse
+	PacketTypeJoinLobbyRequest
+	PacketTypeJoinLobbyResponse
+	PacketTypeLobbyChatRequest
+	PacketTypeLobbyChatResponse
+	PacketTypeLeaveLobbyRequest
+	PacketTypeLobbyPlayerStatus
+	PacketTypeError
+	PacketTypeStartGame
+	PacketTypeGameJoin
+	PacketTypeGameMessage
+	PacketTypeQueueForGameRequest
+	PacketTypeQueueCancelRequest
+	PacketTypeMatchFound
+)
+
+// PacketHeader is embedded in every packet struct and carries the type tag
+// used to route and decode the packet.
+type PacketHeader struct {
+	Type PacketType `json:"type"`
+}
+
+// GetHeader satisfies the Packet interface.
+func (h PacketHeader) GetHeader() PacketHeader {
+	return h
+}
+
+// Packet is implemented by every request/response/event struct sent over
+// the wire.
+type Packet interface {
+	GetHeader() PacketHeader
+}
+
+type VersionPacket struct {
+	PacketHeader
+	Version string `json:"version"`
+}
+
+type ExitPacket struct {
+	PacketHeader
+}
+
+type LoginRequestPacket struct {
+	PacketHeader
+	Name  string `json:"name"`
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+type CreateLobbyRequestPacket struct {
+	PacketHeader
+	Name string `json:"name"`
+}
+
+type CreateLobbyResponsePacket struct {
+	PacketHeader
+	LobbyID string `json:"lobby_id"`
+}
+
+// Card pile identifiers used by CardPileRequestPacket.
+const (
+	CardPileArchive uint8 = iota
+)
+
+type CardPileRequestPacket struct {
+	PacketHeader
+	Pile uint8 `json:"pile"`
+}
+
+type PlayerListRequestPacket struct {
+	PacketHeader
+}
+
+type PlayerListEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PlayerList struct {
+	Players []PlayerListEntry `json:"players"`
+	Count   uint              `json:"count"`
+}
+
+type PlayerListResponsePacket struct {
+	PacketHeader
+	PlayerList
+}
+
+type GlobalChatRequestPacket struct {
+	PacketHeader
+	Message string `json:"message"`
+}
+
+type GlobalChatResponsePacket struct {
+	PacketHeader
+	From    string `json:"from"`
+	Message string `json:"message"`
+}
+
+type LobbyListRequestPacket struct {
+	PacketHeader
+}
+
+type LobbyListEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type LobbyList struct {
+	Lobbies []LobbyListEntry `json:"lobbies"`
+	Count   uint             `json:"count"`
+}
+
+type LobbyListResponsePacket struct {
+	PacketHeader
+	LobbyList
+}
+
+type JoinLobbyRequestPacket struct {
+	PacketHeader
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type JoinLobbyResponsePacket struct {
+	PacketHeader
+	Name    string `json:"name"`
+	LobbyID string `json:"lobby_id"`
+	Success bool   `json:"success"`
+}
+
+type LobbyChatRequestPacket struct {
+	PacketHeader
+	Message string `json:"message"`
+}
+
+type LobbyChatResponsePacket struct {
+	PacketHeader
+	LobbyID string `json:"lobby_id"`
+	From    string `json:"from"`
+	Message string `json:"message"`
+}
+
+type LeaveLobbyRequestPacket struct {
+	PacketHeader
+}
+
+// LobbyPlayerStatusPacket is pushed to every other occupant of a lobby when
+// a player joins or leaves it.
+type LobbyPlayerStatusPacket struct {
+	PacketHeader
+	LobbyID    string `json:"lobby_id"`
+	PlayerName string `json:"player_name"`
+	Joined     bool   `json:"joined"`
+}
+
+type ErrorPacket struct {
+	PacketHeader
+	Message string `json:"message"`
+}
+
+// StartGamePacket is pushed to every player in a lobby once the server has
+// assembled a GameSession for it. The client dials Address and sends a
+// GameJoinPacket carrying GameID and Token as its first frame.
+type StartGamePacket struct {
+	PacketHeader
+	Address string `json:"address"`
+	GameID  string `json:"game_id"`
+	Token   string `json:"token"`
+}
+
+// GameJoinPacket is the first frame a client sends on the game connection to
+// be paired into the right GameSession.
+type GameJoinPacket struct {
+	PacketHeader
+	GameID string `json:"game_id"`
+	Token  string `json:"token"`
+}
+
+// GameMessagePacket carries opaque in-game traffic between paired clients;
+// the server doesn't interpret Payload, it just relays it.
+type GameMessagePacket struct {
+	PacketHeader
+	Payload string `json:"payload"`
+}
+
+type QueueForGameRequestPacket struct {
+	PacketHeader
+}
+
+type QueueCancelRequestPacket struct {
+	PacketHeader
+}
+
+// MatchFoundPacket is pushed to every player the Usher has just grouped
+// into a match, naming the lobby they've been auto-added to.
+type MatchFoundPacket struct {
+	PacketHeader
+	LobbyID string `json:"lobby_id"`
+}
+
+// packetBodies maps a PacketType to a decoder that unmarshals a raw frame
+// into the concrete struct named by its header, returning it by value —
+// handlers type-switch/assert on the value type, not a pointer.
+var packetBodies = map[PacketType]func([]byte) (Packet, error){
+	PacketTypeVersionRequest: func(b []byte) (Packet, error) { var p VersionPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeExit:           func(b []byte) (Packet, error) { var p ExitPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLoginRequest:   func(b []byte) (Packet, error) { var p LoginRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeCreateLobbyRequest: func(b []byte) (Packet, error) {
+		var p CreateLobbyRequestPacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeCreateLobbyResponse: func(b []byte) (Packet, error) {
+		var p CreateLobbyResponsePacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeCardPileRequest:   func(b []byte) (Packet, error) { var p CardPileRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypePlayerListRequest: func(b []byte) (Packet, error) { var p PlayerListRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypePlayerListResponse: func(b []byte) (Packet, error) {
+		var p PlayerListResponsePacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeGlobalChatRequest: func(b []byte) (Packet, error) { var p GlobalChatRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeGlobalChatResponse: func(b []byte) (Packet, error) {
+		var p GlobalChatResponsePacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeLobbyListRequest:  func(b []byte) (Packet, error) { var p LobbyListRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLobbyListResponse: func(b []byte) (Packet, error) { var p LobbyListResponsePacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeJoinLobbyRequest:  func(b []byte) (Packet, error) { var p JoinLobbyRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeJoinLobbyResponse: func(b []byte) (Packet, error) { var p JoinLobbyResponsePacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLobbyChatRequest:  func(b []byte) (Packet, error) { var p LobbyChatRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLobbyChatResponse: func(b []byte) (Packet, error) { var p LobbyChatResponsePacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLeaveLobbyRequest: func(b []byte) (Packet, error) { var p LeaveLobbyRequestPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeLobbyPlayerStatus: func(b []byte) (Packet, error) { var p LobbyPlayerStatusPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeError:             func(b []byte) (Packet, error) { var p ErrorPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeStartGame:         func(b []byte) (Packet, error) { var p StartGamePacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeGameJoin:          func(b []byte) (Packet, error) { var p GameJoinPacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeGameMessage:       func(b []byte) (Packet, error) { var p GameMessagePacket; e := json.Unmarshal(b, &p); return p, e },
+	PacketTypeQueueForGameRequest: func(b []byte) (Packet, error) {
+		var p QueueForGameRequestPacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeQueueCancelRequest: func(b []byte) (Packet, error) {
+		var p QueueCancelRequestPacket
+		e := json.Unmarshal(b, &p)
+		return p, e
+	},
+	PacketTypeMatchFound: func(b []byte) (Packet, error) { var p MatchFoundPacket; e := json.Unmarshal(b, &p); return p, e },
+}
+
+// WritePacket encodes packet as JSON and writes it to conn behind a 4-byte
+// big-endian length prefix.
+func WritePacket(conn net.Conn, packet Packet) error {
+	body, e := json.Marshal(packet)
+
+	if e != nil {
+		return e
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(body)))
+
+	if _, e := conn.Write(length[:]); e != nil {
+		return e
+	}
+
+	_, e = conn.Write(body)
+	return e
+}
+
+// ReadPacket reads a single length-prefixed frame from conn and decodes it
+// into the concrete packet type named by its header.
+func ReadPacket(conn net.Conn) (Packet, error) {
+	var length [4]byte
+
+	if _, e := io.ReadFull(conn, length[:]); e != nil {
+		return nil, e
+	}
+
+	body := make([]byte, binary.BigEndian.Uint32(length[:]))
+
+	if _, e := io.ReadFull(conn, body); e != nil {
+		return nil, e
+	}
+
+	var header PacketHeader
+
+	if e := json.Unmarshal(body, &header); e != nil {
+		return nil, e
+	}
+
+	decode, ok := packetBodies[header.Type]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown packet type %d", header.Type)
+	}
+
+	return decode(body)
+}
@@ -0,0 +1,42 @@
+package kfnetwork
+
+import (
+	"net"
+	"sync"
+)
+
+// Player is a logged-in connection tracked by the Server.
+type Player struct {
+	ID     string
+	Name   string
+	Client net.Conn
+
+	lobbyMu sync.Mutex
+	// lobby is the lobby this player currently occupies, if any. It's
+	// written from the player's own connection goroutine (join/leave) and
+	// from the Usher goroutine (matchmaking), so it's guarded by lobbyMu
+	// rather than accessed directly — use Lobby/SetLobby.
+	lobby *Lobby
+}
+
+// NewPlayer returns an empty Player ready to be populated from a
+// LoginRequestPacket.
+func NewPlayer() *Player {
+	return new(Player)
+}
+
+// Lobby returns the lobby this player currently occupies, or nil.
+func (p *Player) Lobby() *Lobby {
+	p.lobbyMu.Lock()
+	defer p.lobbyMu.Unlock()
+
+	return p.lobby
+}
+
+// SetLobby records the lobby this player currently occupies; pass nil when
+// they leave one.
+func (p *Player) SetLobby(lobby *Lobby) {
+	p.lobbyMu.Lock()
+	p.lobby = lobby
+	p.lobbyMu.Unlock()
+}
@@ -0,0 +1,85 @@
+package kfnetwork
+
+import (
+	"sync"
+	"time"
+)
+
+// maxRateLimitViolations is how many times a connection can get throttled
+// before the server gives up on it and closes the connection.
+const maxRateLimitViolations = 5
+
+// rateLimit is the configuration for a token bucket: how fast it refills
+// and how many tokens it can hold at once.
+type rateLimit struct {
+	rps   float64
+	burst float64
+}
+
+// tokenBucket is a classic token-bucket limiter: tokens refill continuously
+// at rps and Allow consumes one if available.
+type tokenBucket struct {
+	mu     sync.Mutex
+	config rateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(config rateLimit) *tokenBucket {
+	return &tokenBucket{config: config, tokens: config.burst, last: time.Now()}
+}
+
+// Allow reports whether a request may proceed, consuming a token if so.
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.config.rps
+	b.last = now
+
+	if b.tokens > b.config.burst {
+		b.tokens = b.config.burst
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// connRateLimiter tracks the per-packet-type token buckets and violation
+// count for a single connection.
+type connRateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[PacketType]*tokenBucket
+	violations int
+}
+
+func newConnRateLimiter() *connRateLimiter {
+	return &connRateLimiter{buckets: make(map[PacketType]*tokenBucket)}
+}
+
+func (c *connRateLimiter) bucketFor(t PacketType, config rateLimit) *tokenBucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucket, ok := c.buckets[t]
+
+	if !ok {
+		bucket = newTokenBucket(config)
+		c.buckets[t] = bucket
+	}
+
+	return bucket
+}
+
+func (c *connRateLimiter) recordViolation() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.violations++
+	return c.violations
+}
@@ -0,0 +1,68 @@
+package kfnetwork
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsUpToBurst(t *testing.T) {
+	b := newTokenBucket(rateLimit{rps: 1, burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Fatalf("expected token %d within burst to be allowed", i)
+		}
+	}
+
+	if b.Allow() {
+		t.Fatal("expected bucket to be exhausted after burst tokens were consumed")
+	}
+}
+
+func TestTokenBucketRefillsOverTime(t *testing.T) {
+	b := newTokenBucket(rateLimit{rps: 10, burst: 1})
+
+	if !b.Allow() {
+		t.Fatal("expected the initial token to be allowed")
+	}
+
+	if b.Allow() {
+		t.Fatal("expected bucket to be empty immediately after consuming its only token")
+	}
+
+	// Back-date last so Allow sees enough elapsed time to refill a token
+	// without the test actually sleeping.
+	b.mu.Lock()
+	b.last = b.last.Add(-200 * time.Millisecond) // well over the 100ms needed at 10rps
+	b.mu.Unlock()
+
+	if !b.Allow() {
+		t.Fatal("expected bucket to have refilled a token after 200ms at 10rps")
+	}
+}
+
+func TestConnRateLimiterTracksViolationsUntilThreshold(t *testing.T) {
+	limiter := newConnRateLimiter()
+
+	for i := 1; i < maxRateLimitViolations; i++ {
+		if v := limiter.recordViolation(); v != i {
+			t.Fatalf("violation %d: got count %d, want %d", i, v, i)
+		}
+	}
+
+	if v := limiter.recordViolation(); v != maxRateLimitViolations {
+		t.Fatalf("final violation: got count %d, want %d", v, maxRateLimitViolations)
+	}
+}
+
+func TestConnRateLimiterBucketForReusesBucketPerPacketType(t *testing.T) {
+	limiter := newConnRateLimiter()
+	config := rateLimit{rps: 1, burst: 1}
+
+	first := limiter.bucketFor(PacketTypeLoginRequest, config)
+	second := limiter.bucketFor(PacketTypeLoginRequest, config)
+
+	if first != second {
+		t.Fatal("expected repeated bucketFor calls for the same packet type to return the same bucket")
+	}
+}
@@ -0,0 +1,255 @@
+package kfnetwork
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Server holds the shared state for a running KeyForge network server:
+// logged-in players and the lobbies they've formed.
+type Server struct {
+	Clients []*Player
+	Lobbies []*Lobby
+	Debug   bool
+
+	mu sync.Mutex
+
+	// GameAddress is the address the in-game traffic listener was started
+	// on, set by ListenGame. Empty until ListenGame has been called.
+	GameAddress  string
+	gameListener net.Listener
+	gameMu       sync.Mutex
+	gameSessions map[string]*GameSession
+
+	// MatchSize is how many players the Usher groups into a lobby; it
+	// defaults to DefaultMatchSize the first time StartUsher runs.
+	MatchSize int
+	queueMu   sync.Mutex
+	queue     []*Player
+	usherWake chan struct{}
+
+	rateLimitMu      sync.Mutex
+	defaultRateLimit rateLimit
+	packetRateLimits map[PacketType]rateLimit
+
+	connLimitersMu sync.Mutex
+	connLimiters   map[net.Conn]*connRateLimiter
+
+	handlersMu sync.Mutex
+	handlers   map[PacketType]HandlerFunc
+}
+
+// NewServer returns a Server with the built-in protocol handlers
+// registered and ready to accept connections.
+func NewServer() *Server {
+	s := &Server{
+		gameSessions:     make(map[string]*GameSession),
+		usherWake:        make(chan struct{}, 1),
+		defaultRateLimit: rateLimit{rps: 10, burst: 20},
+		packetRateLimits: map[PacketType]rateLimit{
+			PacketTypeGlobalChatRequest: {rps: 2, burst: 4},
+			PacketTypeLobbyChatRequest:  {rps: 2, burst: 4},
+			PacketTypeLobbyListRequest:  {rps: 1, burst: 2},
+			PacketTypePlayerListRequest: {rps: 1, burst: 2},
+			PacketTypeLoginRequest:      {rps: 0.2, burst: 1},
+		},
+		connLimiters: make(map[net.Conn]*connRateLimiter),
+		handlers:     make(map[PacketType]HandlerFunc),
+	}
+
+	s.registerDefaultHandlers()
+	return s
+}
+
+// SetRateLimit overrides the token-bucket configuration used for
+// packetType on every connection. Buckets already handed out keep their
+// existing configuration; only new ones pick up the change.
+func (s *Server) SetRateLimit(packetType PacketType, rps float64, burst float64) {
+	s.rateLimitMu.Lock()
+	s.packetRateLimits[packetType] = rateLimit{rps: rps, burst: burst}
+	s.rateLimitMu.Unlock()
+}
+
+// allowPacket enforces the per-connection, per-packet-type rate limit for
+// packetType on client. On a breach it sends the client an ErrorPacket and,
+// after repeated violations, closes the connection.
+func (s *Server) allowPacket(client net.Conn, packetType PacketType) bool {
+	limiter := s.connRateLimiterFor(client)
+	bucket := limiter.bucketFor(packetType, s.rateLimitFor(packetType))
+
+	if bucket.Allow() {
+		return true
+	}
+
+	retryMs := 1000
+	if bucket.config.rps > 0 {
+		retryMs = int(1000 / bucket.config.rps)
+	}
+
+	s.SendErrorPacket(client, fmt.Sprintf("rate limited, retry after %dms", retryMs))
+
+	if limiter.recordViolation() >= maxRateLimitViolations {
+		Logger().Error(fmt.Sprintf("closing %s after repeated rate limit violations", client.RemoteAddr()))
+		s.CloseConnection(client)
+	}
+
+	return false
+}
+
+func (s *Server) rateLimitFor(packetType PacketType) rateLimit {
+	s.rateLimitMu.Lock()
+	defer s.rateLimitMu.Unlock()
+
+	if config, ok := s.packetRateLimits[packetType]; ok {
+		return config
+	}
+
+	return s.defaultRateLimit
+}
+
+func (s *Server) connRateLimiterFor(client net.Conn) *connRateLimiter {
+	s.connLimitersMu.Lock()
+	defer s.connLimitersMu.Unlock()
+
+	limiter, ok := s.connLimiters[client]
+
+	if !ok {
+		limiter = newConnRateLimiter()
+		s.connLimiters[client] = limiter
+	}
+
+	return limiter
+}
+
+// removeConnRateLimiter drops client's rate limiter state. Called from
+// CloseConnection so it runs no matter which path closed the connection —
+// a clean ExitPacket or allowPacket itself giving up on an abusive
+// client — instead of leaking an entry keyed on a now-dead net.Conn.
+func (s *Server) removeConnRateLimiter(client net.Conn) {
+	s.connLimitersMu.Lock()
+	delete(s.connLimiters, client)
+	s.connLimitersMu.Unlock()
+}
+
+func (s *Server) AddPlayer(player *Player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Clients = append(s.Clients, player)
+}
+
+func (s *Server) RemovePlayer(player *Player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.Clients {
+		if p == player {
+			s.Clients = append(s.Clients[:i], s.Clients[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *Server) FindPlayerByConnection(conn net.Conn) (*Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.Clients {
+		if p.Client == conn {
+			return p, nil
+		}
+	}
+
+	return nil, errors.New("no player found for connection")
+}
+
+// AddLobby creates a new lobby named name, seats owner in it, and tracks it
+// on the server.
+func (s *Server) AddLobby(owner *Player, name string) *Lobby {
+	lobby := NewLobby(name)
+	lobby.AddPlayer(owner)
+
+	s.mu.Lock()
+	s.Lobbies = append(s.Lobbies, lobby)
+	s.mu.Unlock()
+
+	return lobby
+}
+
+func (s *Server) FindLobbyByID(id string) (*Lobby, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, lobby := range s.Lobbies {
+		if lobby.ID() == id {
+			return lobby, nil
+		}
+	}
+
+	return nil, errors.New("no lobby found with that ID")
+}
+
+func (s *Server) FindLobbyByName(name string) (*Lobby, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, lobby := range s.Lobbies {
+		if lobby.Name() == name {
+			return lobby, nil
+		}
+	}
+
+	return nil, errors.New("no lobby found with that name")
+}
+
+// CloseConnection closes conn and drops any per-connection state keyed on
+// it, so a connection can't leak rate limiter state by going away any way
+// other than a clean ExitPacket.
+func (s *Server) CloseConnection(conn net.Conn) {
+	conn.Close()
+	s.removeConnRateLimiter(conn)
+}
+
+func (s *Server) SendErrorPacket(conn net.Conn, message string) error {
+	packet := ErrorPacket{Message: message}
+	packet.Type = PacketTypeError
+
+	return WritePacket(conn, packet)
+}
+
+func (s *Server) SendCreateLobbyResponse(player *Player, lobbyID string) error {
+	packet := CreateLobbyResponsePacket{LobbyID: lobbyID}
+	packet.Type = PacketTypeCreateLobbyResponse
+
+	return WritePacket(player.Client, packet)
+}
+
+func (s *Server) SendPlayerListResponse(player *Player, playerList PlayerList) error {
+	packet := PlayerListResponsePacket{PlayerList: playerList}
+	packet.Type = PacketTypePlayerListResponse
+
+	return WritePacket(player.Client, packet)
+}
+
+func (s *Server) SendGlobalChatResponse(player *Player, from string, message string) error {
+	packet := GlobalChatResponsePacket{From: from, Message: message}
+	packet.Type = PacketTypeGlobalChatResponse
+
+	return WritePacket(player.Client, packet)
+}
+
+func (s *Server) SendLobbyListResponse(player *Player, lobbyList LobbyList) error {
+	packet := LobbyListResponsePacket{LobbyList: lobbyList}
+	packet.Type = PacketTypeLobbyListResponse
+
+	return WritePacket(player.Client, packet)
+}
+
+func (s *Server) SendJoinLobbyResponse(player *Player, name string, lobbyID string, success bool) error {
+	packet := JoinLobbyResponsePacket{Name: name, LobbyID: lobbyID, Success: success}
+	packet.Type = PacketTypeJoinLobbyResponse
+
+	return WritePacket(player.Client, packet)
+}
@@ -0,0 +1,113 @@
+package kfnetwork
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ListenGame starts the in-game traffic listener on address, separate from
+// the lobby/login socket. Clients dial this address and
+// send a GameJoinPacket as their first frame to be paired into a session.
+func (s *Server) ListenGame(address string) error {
+	listener, e := net.Listen("tcp", address)
+
+	if e != nil {
+		return e
+	}
+
+	s.GameAddress = address
+	s.gameListener = listener
+
+	go s.acceptGameConnections(listener)
+	return nil
+}
+
+func (s *Server) acceptGameConnections(listener net.Listener) {
+	for {
+		conn, e := listener.Accept()
+
+		if e != nil {
+			return
+		}
+
+		go s.handleGameConnection(conn)
+	}
+}
+
+func (s *Server) handleGameConnection(conn net.Conn) {
+	packet, e := ReadPacket(conn)
+
+	if e != nil {
+		conn.Close()
+		return
+	}
+
+	join, ok := packet.(GameJoinPacket)
+
+	if !ok {
+		s.SendErrorPacket(conn, "expected a game join packet")
+		conn.Close()
+		return
+	}
+
+	session, e := s.findGameSession(join.GameID)
+
+	if e != nil || session.token != join.Token {
+		s.SendErrorPacket(conn, "invalid game ID or token")
+		conn.Close()
+		return
+	}
+
+	session.Join(conn)
+}
+
+// StartGame mints a game session for lobby, notifies each of its players
+// with a StartGamePacket, and returns the session so callers can track it.
+func (s *Server) StartGame(lobby *Lobby) (*GameSession, error) {
+	if s.GameAddress == "" {
+		return nil, errors.New("game listener is not running")
+	}
+
+	gameID := newID()
+	token := newID()
+
+	session := newGameSession(gameID, token, func() {
+		s.removeGameSession(gameID)
+	})
+
+	s.gameMu.Lock()
+	s.gameSessions[gameID] = session
+	s.gameMu.Unlock()
+
+	for _, player := range lobby.Players() {
+		packet := StartGamePacket{Address: s.GameAddress, GameID: gameID, Token: token}
+		packet.Type = PacketTypeStartGame
+
+		if e := WritePacket(player.Client, packet); e != nil {
+			logEntry := fmt.Sprintf("StartGame: failed to notify %s: %s", player.Name, e.Error())
+			Logger().Error(logEntry)
+		}
+	}
+
+	return session, nil
+}
+
+func (s *Server) findGameSession(gameID string) (*GameSession, error) {
+	s.gameMu.Lock()
+	defer s.gameMu.Unlock()
+
+	session, ok := s.gameSessions[gameID]
+
+	if !ok {
+		return nil, errors.New("no game session with that ID")
+	}
+
+	return session, nil
+}
+
+func (s *Server) removeGameSession(gameID string) {
+	s.gameMu.Lock()
+	delete(s.gameSessions, gameID)
+	s.gameMu.Unlock()
+}
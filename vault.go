@@ -0,0 +1,20 @@
+package kfnetwork
+
+import "errors"
+
+// VaultUser is the profile record returned by the auth vault for a login
+// token.
+type VaultUser struct {
+	ID   string
+	Name string
+}
+
+// RetrieveProfile does not contact any vault or account service: it returns
+// an error for every token, so no login request succeeds.
+func RetrieveProfile(token string) (VaultUser, error) {
+	if token == "" {
+		return VaultUser{}, errors.New("empty token")
+	}
+
+	return VaultUser{}, errors.New("vault lookup not implemented")
+}